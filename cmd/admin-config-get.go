@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/minio/cli"
@@ -25,17 +27,28 @@ import (
 	"github.com/minio/minio/pkg/madmin"
 )
 
+var adminConfigGetFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "defaults",
+		Usage: "show which keys are still at their default value vs overridden",
+	},
+	cli.StringFlag{
+		Name:  "diff",
+		Usage: "compare this config against another alias, key by key",
+	},
+}
+
 var adminConfigGetCmd = cli.Command{
 	Name:   "get",
 	Usage:  "get config of a MinIO server/cluster",
 	Before: setGlobalsFromContext,
 	Action: mainAdminConfigGet,
-	Flags:  globalFlags,
+	Flags:  append(adminConfigGetFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} TARGET
+  {{.HelpName}} [FLAGS] TARGET
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -55,18 +68,62 @@ EXAMPLES:
      {{.Prompt}} {{.HelpName}} myminio/ compression
      # Compression settings for csv and text files only
      compression extensions=".txt,.csv" mime_types="text/*" state="on"
+
+  4. Show which keys are still at their defaults vs overridden.
+     {{.Prompt}} {{.HelpName}} --defaults myminio/ compression
+
+  5. Compare the region settings between two clusters.
+     {{.Prompt}} {{.HelpName}} --diff otherminio/ myminio/ region
 `,
 }
 
+// keyValue is a single flattened subsystem/key/value triple, used so
+// downstream tools can consume configGetMessage.Keys instead of parsing the
+// free-form Value.String() output. Keys is only filled when --defaults is
+// given; without it the JSON carries no keys field.
+type keyValue struct {
+	Subsystem string `json:"subsystem"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	IsDefault bool   `json:"isDefault,omitempty"`
+}
+
 // configGetMessage container to hold locks information.
 type configGetMessage struct {
-	Status string         `json:"status"`
-	Value  madmin.Targets `json:"value"`
+	Status string              `json:"status"`
+	Value  madmin.Targets      `json:"value"`
+	Keys   []keyValue          `json:"keys,omitempty"`
+	Diff   []configKVDiffEntry `json:"diff,omitempty"`
 }
 
-// String colorized service status message.
-func (u configGetMessage) String() string {
-	return u.Value.String()
+// String colorized service status message. --defaults/--diff only add fields
+// to the struct, so they need to be rendered here too or they're invisible
+// outside --json (the JSON() method already marshals every field).
+func (u configGetMessage) String() (msg string) {
+	msg = u.Value.String()
+
+	if len(u.Keys) > 0 {
+		keys := append([]keyValue{}, u.Keys...)
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].Subsystem != keys[j].Subsystem {
+				return keys[i].Subsystem < keys[j].Subsystem
+			}
+			return keys[i].Key < keys[j].Key
+		})
+		for _, k := range keys {
+			state := "overridden"
+			if k.IsDefault {
+				state = "default"
+			}
+			msg += fmt.Sprintf("\n%s %s=%s (%s)", k.Subsystem, k.Key, k.Value, state)
+		}
+	}
+
+	for _, d := range u.Diff {
+		msg += "\n" + strings.TrimSuffix(formatConfigKVDiffEntry(d), "\n")
+	}
+
+	return msg
 }
 
 // JSON jsonified service status Message message.
@@ -92,19 +149,49 @@ func mainAdminConfigGet(ctx *cli.Context) error {
 	// Get the alias parameter from cli
 	args := ctx.Args()
 	aliasedURL := args.Get(0)
+	key := strings.Join(args.Tail(), " ")
 
 	// Create a new MinIO Admin Client
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
 
 	// Call get config API
-	buf, e := client.GetConfigKV(strings.Join(args.Tail(), " "))
+	buf, e := client.GetConfigKV(key)
 	fatalIf(probe.NewError(e), "Cannot get server '%s' config", args.Tail())
 
+	msg := configGetMessage{Value: buf}
+
+	if ctx.Bool("defaults") {
+		schema, e := fetchConfigSchema(client, aliasedURL)
+		fatalIf(probe.NewError(e), "Unable to fetch config schema for '%s'.", aliasedURL)
+
+		parsed := parseConfigKV(buf.String())
+		for subsystem, kv := range parsed {
+			sub := schema.findSubsystem(subsystem)
+			for k, v := range kv {
+				isDefault := false
+				if sub != nil {
+					if keySchema := sub.findKey(k); keySchema != nil {
+						isDefault = keySchema.Default == v
+					}
+				}
+				msg.Keys = append(msg.Keys, keyValue{Subsystem: subsystem, Key: k, Value: v, IsDefault: isDefault})
+			}
+		}
+	}
+
+	if diffAlias := ctx.String("diff"); diffAlias != "" {
+		otherClient, err := newAdminClient(diffAlias)
+		fatalIf(err, "Unable to initialize admin connection.")
+
+		otherBuf, e := otherClient.GetConfigKV(key)
+		fatalIf(probe.NewError(e), "Cannot get server '%s' config", args.Tail())
+
+		msg.Diff = diffConfigKV(otherBuf.String(), buf.String())
+	}
+
 	// Print
-	printMsg(configGetMessage{
-		Value: buf,
-	})
+	printMsg(msg)
 
 	return nil
 }
@@ -17,26 +17,46 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/madmin"
 )
 
+var adminConfigHistoryRestoreFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "show the diff of what would change and exit without restoring",
+	},
+	cli.BoolFlag{
+		Name:  "yes",
+		Usage: "skip the confirmation prompt and restore directly",
+	},
+	cli.BoolFlag{
+		Name:  "diff-only",
+		Usage: "print only the diff (no restore, no prompt)",
+	},
+}
+
 var adminConfigHistoryRestoreCmd = cli.Command{
 	Name:   "restore",
 	Usage:  "restore a history key value on MinIO server",
 	Before: setGlobalsFromContext,
 	Action: mainAdminConfigHistoryRestore,
-	Flags:  globalFlags,
+	Flags:  append(adminConfigHistoryRestoreFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} TARGET RESTOREID
+  {{.HelpName}} [FLAGS] TARGET RESTOREID
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -44,18 +64,47 @@ FLAGS:
 EXAMPLES:
   1. Restore 'restore-id' history key value on MinIO server.
      {{.Prompt}} {{.HelpName}} play/ <restore-id>
+
+  2. Preview what 'restore-id' would change without applying it.
+     {{.Prompt}} {{.HelpName}} --dry-run play/ <restore-id>
+
+  3. Restore 'restore-id' without the confirmation prompt.
+     {{.Prompt}} {{.HelpName}} --yes play/ <restore-id>
 `,
 }
 
+// configKVDiffEntry represents a single changed/added/removed key within a subsystem.
+type configKVDiffEntry struct {
+	Subsystem string `json:"subsystem"`
+	Key       string `json:"key"`
+	Type      string `json:"type"` // "added", "removed" or "changed"
+	OldValue  string `json:"oldValue,omitempty"`
+	NewValue  string `json:"newValue,omitempty"`
+}
+
 // configHistoryRestoreMessage container to hold locks information.
 type configHistoryRestoreMessage struct {
-	Status      string `json:"status"`
-	RestoreID   string `json:"restoreID"`
+	Status      string               `json:"status"`
+	RestoreID   string               `json:"restoreID"`
+	DryRun      bool                 `json:"dryRun"`
+	Diff        []configKVDiffEntry  `json:"diff,omitempty"`
 	targetAlias string
 }
 
-// String colorized service status message.
+// String colorized service status message. Always renders Diff: callers that
+// already showed the diff once (the interactive confirm path) pass a message
+// with Diff cleared so it isn't printed a second time; --yes and --json runs
+// never show it elsewhere, so it must still appear here.
 func (u configHistoryRestoreMessage) String() (msg string) {
+	for _, d := range u.Diff {
+		msg += formatConfigKVDiffEntry(d)
+	}
+
+	if u.DryRun {
+		msg += console.Colorize("ConfigHistoryRestoreMessage", "(dry run) Nothing was restored.\n")
+		return msg
+	}
+
 	suggestion := fmt.Sprintf("mc admin service restart %s", u.targetAlias)
 	msg += console.Colorize("ConfigHistoryRestoreMessage",
 		fmt.Sprintf("Please restart your server with `%s`.\n", suggestion))
@@ -72,6 +121,123 @@ func (u configHistoryRestoreMessage) JSON() string {
 	return string(statusJSONBytes)
 }
 
+// sensitiveConfigKeys are masked in the diff output so secrets never hit a terminal or CI log.
+var sensitiveConfigKeys = map[string]bool{
+	"secret_key":  true,
+	"password":    true,
+	"sak":         true,
+	"private_key": true,
+}
+
+// maskSensitiveValue redacts a value if its key looks sensitive.
+func maskSensitiveValue(key, value string) string {
+	if sensitiveConfigKeys[strings.ToLower(key)] && value != "" {
+		return "***REDACTED***"
+	}
+	return value
+}
+
+// formatConfigKVDiffEntry renders a single diff entry with +/-/~ markers, matching
+// the colorization convention already used for config messages in this package.
+func formatConfigKVDiffEntry(d configKVDiffEntry) string {
+	switch d.Type {
+	case "added":
+		return console.Colorize("ConfigHistoryRestoreDiffAdd",
+			fmt.Sprintf("+ %s %s=%s\n", d.Subsystem, d.Key, maskSensitiveValue(d.Key, d.NewValue)))
+	case "removed":
+		return console.Colorize("ConfigHistoryRestoreDiffRemove",
+			fmt.Sprintf("- %s %s=%s\n", d.Subsystem, d.Key, maskSensitiveValue(d.Key, d.OldValue)))
+	default: // "changed"
+		return console.Colorize("ConfigHistoryRestoreDiffChange",
+			fmt.Sprintf("~ %s %s=%s -> %s\n", d.Subsystem, d.Key,
+				maskSensitiveValue(d.Key, d.OldValue), maskSensitiveValue(d.Key, d.NewValue)))
+	}
+}
+
+// parseConfigKV parses a raw "subsystem key1=val1 key2=val2" config blob, one
+// subsystem per line, into a subsystem -> key -> value map.
+func parseConfigKV(raw string) map[string]map[string]string {
+	out := map[string]map[string]string{}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := splitConfigKVFields(line)
+		subsystem := fields[0]
+		if out[subsystem] == nil {
+			out[subsystem] = map[string]string{}
+		}
+		for _, kv := range fields[1:] {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			out[subsystem][parts[0]] = strings.Trim(parts[1], `"`)
+		}
+	}
+	return out
+}
+
+// diffConfigKV computes an ordered, per-subsystem/per-key diff between the currently
+// live configuration and the target history entry being restored.
+func diffConfigKV(liveRaw, historyRaw string) []configKVDiffEntry {
+	live := parseConfigKV(liveRaw)
+	target := parseConfigKV(historyRaw)
+
+	subsystems := map[string]bool{}
+	for s := range live {
+		subsystems[s] = true
+	}
+	for s := range target {
+		subsystems[s] = true
+	}
+
+	var subsystemNames []string
+	for s := range subsystems {
+		subsystemNames = append(subsystemNames, s)
+	}
+	sort.Strings(subsystemNames)
+
+	var diff []configKVDiffEntry
+	for _, subsystem := range subsystemNames {
+		liveKV := live[subsystem]
+		targetKV := target[subsystem]
+
+		keys := map[string]bool{}
+		for k := range liveKV {
+			keys[k] = true
+		}
+		for k := range targetKV {
+			keys[k] = true
+		}
+
+		var keyNames []string
+		for k := range keys {
+			keyNames = append(keyNames, k)
+		}
+		sort.Strings(keyNames)
+
+		for _, key := range keyNames {
+			oldValue, oldOK := liveKV[key]
+			newValue, newOK := targetKV[key]
+			switch {
+			// Values are compared raw but stored masked, so the JSON
+			// output never carries a secret either.
+			case !oldOK && newOK:
+				diff = append(diff, configKVDiffEntry{Subsystem: subsystem, Key: key, Type: "added", NewValue: maskSensitiveValue(key, newValue)})
+			case oldOK && !newOK:
+				diff = append(diff, configKVDiffEntry{Subsystem: subsystem, Key: key, Type: "removed", OldValue: maskSensitiveValue(key, oldValue)})
+			case oldValue != newValue:
+				diff = append(diff, configKVDiffEntry{Subsystem: subsystem, Key: key, Type: "changed",
+					OldValue: maskSensitiveValue(key, oldValue), NewValue: maskSensitiveValue(key, newValue)})
+			}
+		}
+	}
+
+	return diff
+}
+
 // checkAdminConfigHistoryRestoreSyntax - validate all the passed arguments
 func checkAdminConfigHistoryRestoreSyntax(ctx *cli.Context) {
 	if !ctx.Args().Present() || len(ctx.Args()) > 2 {
@@ -79,26 +245,129 @@ func checkAdminConfigHistoryRestoreSyntax(ctx *cli.Context) {
 	}
 }
 
+// fetchConfigHistoryEntry finds restoreID among the server's config history
+// and returns its raw "subsystem key=value ..." content, the same data `mc
+// admin config history list` already walks via ListConfigHistoryKV.
+func fetchConfigHistoryEntry(client *madmin.AdminClient, restoreID string) (string, error) {
+	entries, e := client.ListConfigHistoryKV("")
+	if e != nil {
+		return "", e
+	}
+	for _, entry := range entries {
+		if entry.RestoreID == restoreID {
+			return entry.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no history entry found for restore id '%s'", restoreID)
+}
+
+// isStdinTerminal reports whether stdin is an interactive terminal, used to
+// refuse a confirmation prompt that nothing would ever answer instead of
+// hanging (or, worse, reading EOF as an empty non-"y" answer and silently
+// cancelling what looked like a successful scripted restore).
+func isStdinTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return (fi.Mode() & os.ModeCharDevice) != 0
+}
+
+// confirmRestore prompts the user on stdin before a restore is applied.
+func confirmRestore(restoreID string) bool {
+	console.Print(console.Colorize("ConfigHistoryRestoreMessage",
+		fmt.Sprintf("Restore %s? This will overwrite the live configuration. [y/N]: ", restoreID)))
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 func mainAdminConfigHistoryRestore(ctx *cli.Context) error {
 
 	checkAdminConfigHistoryRestoreSyntax(ctx)
 
 	console.SetColor("ConfigHistoryRestoreMessage", color.New(color.FgGreen))
+	console.SetColor("ConfigHistoryRestoreDiffAdd", color.New(color.FgGreen))
+	console.SetColor("ConfigHistoryRestoreDiffRemove", color.New(color.FgRed))
+	console.SetColor("ConfigHistoryRestoreDiffChange", color.New(color.FgYellow))
 
 	// Get the alias parameter from cli
 	args := ctx.Args()
 	aliasedURL := args.Get(0)
+	restoreID := args.Get(1)
+
+	dryRun := ctx.Bool("dry-run")
+	skipConfirm := ctx.Bool("yes")
+	diffOnly := ctx.Bool("diff-only")
 
 	// Create a new MinIO Admin Client
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
 
-	// Call get config API
-	fatalIf(probe.NewError(client.RestoreConfigHistoryKV(args.Get(1))), "Cannot restore server configuration.")
+	// Fetch the current live configuration and the target history entry so we
+	// can show exactly what the restore is about to change. The history
+	// entry comes from the same ListConfigHistoryKV API that backs `mc admin
+	// config history list`, matched by restore ID.
+	liveKV, e := client.GetConfigKV("")
+	fatalIf(probe.NewError(e), "Cannot get current server configuration.")
+
+	historyRaw, e := fetchConfigHistoryEntry(client, restoreID)
+	fatalIf(probe.NewError(e), "Cannot get history entry '%s'.", restoreID)
+
+	diff := diffConfigKV(liveKV.String(), historyRaw)
+
+	if diffOnly && !globalJSON {
+		for _, d := range diff {
+			console.Print(formatConfigKVDiffEntry(d))
+		}
+		return nil
+	}
 
-	// Print
+	if dryRun || diffOnly {
+		printMsg(configHistoryRestoreMessage{
+			RestoreID:   restoreID,
+			DryRun:      true,
+			Diff:        diff,
+			targetAlias: aliasedURL,
+		})
+		return nil
+	}
+
+	// --json does NOT imply --yes: a scripted/CI run that wants to skip the
+	// confirmation prompt must say so explicitly with --yes. If neither is
+	// given and stdin isn't a terminal to answer the prompt on, fail loudly
+	// instead of silently proceeding (or hanging) - overwriting live config
+	// unconfirmed is exactly the mistake this command exists to prevent.
+	diffAlreadyShown := false
+	if !skipConfirm {
+		if !isStdinTerminal() {
+			fatalIf(probe.NewError(fmt.Errorf("refusing to restore without confirmation: stdin is not a terminal to prompt on, pass --yes to confirm non-interactively")),
+				"Cannot confirm restore.")
+		}
+		for _, d := range diff {
+			console.Print(formatConfigKVDiffEntry(d))
+		}
+		diffAlreadyShown = true
+		if !confirmRestore(restoreID) {
+			console.Println(console.Colorize("ConfigHistoryRestoreMessage", "Restore cancelled."))
+			return nil
+		}
+	}
+
+	// Call restore config API
+	fatalIf(probe.NewError(client.RestoreConfigHistoryKV(restoreID)), "Cannot restore server configuration.")
+
+	// Print. Skip re-rendering a diff that was already shown at the
+	// confirmation prompt; --yes and --json runs never showed it, so they
+	// still need it here (String()/JSON() both read this field).
+	finalDiff := diff
+	if diffAlreadyShown {
+		finalDiff = nil
+	}
 	printMsg(configHistoryRestoreMessage{
-		RestoreID:   args.Get(1),
+		RestoreID:   restoreID,
+		Diff:        finalDiff,
 		targetAlias: aliasedURL,
 	})
 
@@ -0,0 +1,348 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// configKeySchema describes one allowed key within a subsystem, enough to
+// validate a `set` payload client-side before it ever reaches the server.
+type configKeySchema struct {
+	Key         string   `json:"key"`
+	Type        string   `json:"type"` // "string", "bool", "duration", "enum", ...
+	EnumValues  []string `json:"enumValues,omitempty"`
+	Default     string   `json:"default"`
+	Description string   `json:"description"`
+}
+
+// configSubsystemSchema describes one subsystem (e.g. "notify_mqtt") and the
+// keys it accepts.
+type configSubsystemSchema struct {
+	Subsystem string            `json:"subsystem"`
+	Keys      []configKeySchema `json:"keys"`
+}
+
+// configSchema is the full, per-alias schema downloaded from a MinIO server's
+// help descriptors and cached locally so repeated `get`/`set` calls don't
+// refetch it.
+type configSchema struct {
+	Subsystems []configSubsystemSchema `json:"subsystems"`
+}
+
+// configSchemaCachePath returns where alias' schema is cached on disk. An
+// aliased URL such as "myminio/" is reduced to its alias "myminio".
+func configSchemaCachePath(alias string) string {
+	alias = strings.SplitN(alias, "/", 2)[0]
+	return filepath.Join(mustGetMcConfigDir(), "config-schema", alias+".json")
+}
+
+// loadCachedConfigSchema reads a previously cached schema for alias, if any.
+func loadCachedConfigSchema(alias string) (*configSchema, bool) {
+	data, e := ioutil.ReadFile(configSchemaCachePath(alias))
+	if e != nil {
+		return nil, false
+	}
+	var schema configSchema
+	if e := json.Unmarshal(data, &schema); e != nil {
+		return nil, false
+	}
+	return &schema, true
+}
+
+// saveCachedConfigSchema writes schema to the on-disk cache for alias.
+func saveCachedConfigSchema(alias string, schema *configSchema) {
+	path := configSchemaCachePath(alias)
+	if e := os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+		return
+	}
+	data, e := json.MarshalIndent(schema, "", " ")
+	if e != nil {
+		return
+	}
+	_ = ioutil.WriteFile(path, data, 0600)
+}
+
+// buildConfigSchemaFromHelp converts the subsystem/key help descriptors
+// returned by the server (`madmin.Help`) into our cacheable configSchema.
+func buildConfigSchemaFromHelp(help madmin.Help) *configSchema {
+	schema := &configSchema{}
+	for _, sub := range help.SubSys {
+		s := configSubsystemSchema{Subsystem: sub.Key}
+		for _, kv := range sub.Help {
+			s.Keys = append(s.Keys, configKeySchema{
+				Key:         kv.Key,
+				Type:        kv.Type,
+				Default:     kv.Value,
+				Description: kv.Description,
+			})
+		}
+		schema.Subsystems = append(schema.Subsystems, s)
+	}
+	return schema
+}
+
+// fetchConfigSchema returns the schema for alias, downloading and caching it
+// from the server the first time it's needed.
+func fetchConfigSchema(client *madmin.AdminClient, alias string) (*configSchema, error) {
+	if schema, ok := loadCachedConfigSchema(alias); ok {
+		return schema, nil
+	}
+
+	help, e := client.HelpConfigKV("", "", false)
+	if e != nil {
+		return nil, e
+	}
+
+	schema := buildConfigSchemaFromHelp(help)
+	saveCachedConfigSchema(alias, schema)
+	return schema, nil
+}
+
+// findSubsystem returns the subsystem schema named name, if any.
+func (s *configSchema) findSubsystem(name string) *configSubsystemSchema {
+	for i := range s.Subsystems {
+		if s.Subsystems[i].Subsystem == name {
+			return &s.Subsystems[i]
+		}
+	}
+	return nil
+}
+
+// findKey returns the key schema named name within the subsystem, if any.
+func (s *configSubsystemSchema) findKey(name string) *configKeySchema {
+	for i := range s.Keys {
+		if s.Keys[i].Key == name {
+			return &s.Keys[i]
+		}
+	}
+	return nil
+}
+
+// didYouMean returns the closest candidate name to want, for use in
+// actionable "unknown subsystem/key" error messages.
+func didYouMean(want string, candidates []string) string {
+	best := ""
+	bestDist := -1
+	for _, c := range candidates {
+		d := levenshtein(want, c)
+		if bestDist == -1 || d < bestDist {
+			bestDist = d
+			best = c
+		}
+	}
+	if best == "" || bestDist > len(want) {
+		return ""
+	}
+	return best
+}
+
+// levenshtein computes the edit distance between a and b.
+func levenshtein(a, b string) int {
+	la, lb := len(a), len(b)
+	dist := make([][]int, la+1)
+	for i := range dist {
+		dist[i] = make([]int, lb+1)
+		dist[i][0] = i
+	}
+	for j := 0; j <= lb; j++ {
+		dist[0][j] = j
+	}
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			dist[i][j] = min3(dist[i-1][j]+1, dist[i][j-1]+1, dist[i-1][j-1]+cost)
+		}
+	}
+	return dist[la][lb]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// splitConfigKVFields splits a "subsystem key1=val1 key2=\"val with spaces\""
+// line on whitespace, except inside a double-quoted value, so a value like
+// comment="weekly backup" survives as one field instead of being torn apart
+// at the space and silently dropped (strings.Fields has no notion of
+// quoting). Shared by parseConfigKV, validateConfigSetKV and
+// expandConfigKVArgs so all three tokenize config KV blobs the same way.
+func splitConfigKVFields(line string) []string {
+	var fields []string
+	var cur strings.Builder
+	inQuotes := false
+
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(r)
+		case r == ' ' || r == '\t':
+			if inQuotes {
+				cur.WriteRune(r)
+				continue
+			}
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+
+	return fields
+}
+
+// validateConfigSetKV validates "subsystem key1=val1 key2=val2 ..." against
+// schema before it is sent to the server, returning an actionable error on
+// the first problem found (unknown subsystem, unknown key, or a value outside
+// an enum's allowed set). A key's Type is not checked, and the enum check
+// only applies to keys whose EnumValues are listed, which
+// buildConfigSchemaFromHelp does not fill in.
+func validateConfigSetKV(schema *configSchema, args string) *probe.Error {
+	fields := splitConfigKVFields(args)
+	if len(fields) == 0 {
+		return probe.NewError(fmt.Errorf("no subsystem specified"))
+	}
+
+	subsystemName := fields[0]
+	sub := schema.findSubsystem(subsystemName)
+	if sub == nil {
+		var names []string
+		for _, s := range schema.Subsystems {
+			names = append(names, s.Subsystem)
+		}
+		sort.Strings(names)
+		msg := fmt.Sprintf("unknown subsystem '%s'", subsystemName)
+		if suggestion := didYouMean(subsystemName, names); suggestion != "" {
+			msg += fmt.Sprintf(", did you mean '%s'?", suggestion)
+		}
+		return probe.NewError(fmt.Errorf(msg))
+	}
+
+	for _, kv := range fields[1:] {
+		parts := strings.SplitN(kv, "=", 2)
+		key := parts[0]
+		keySchema := sub.findKey(key)
+		if keySchema == nil {
+			var names []string
+			for _, k := range sub.Keys {
+				names = append(names, k.Key)
+			}
+			sort.Strings(names)
+			msg := fmt.Sprintf("unknown key '%s' for subsystem '%s'", key, subsystemName)
+			if suggestion := didYouMean(key, names); suggestion != "" {
+				msg += fmt.Sprintf(", did you mean '%s'?", suggestion)
+			}
+			return probe.NewError(fmt.Errorf(msg))
+		}
+
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.Trim(parts[1], `"`)
+
+		if keySchema.Type == "enum" && len(keySchema.EnumValues) > 0 {
+			valid := false
+			for _, v := range keySchema.EnumValues {
+				if v == value {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return probe.NewError(fmt.Errorf("value '%s' for key '%s' must be one of %s",
+					value, key, strings.Join(keySchema.EnumValues, ", ")))
+			}
+		}
+	}
+
+	return nil
+}
+
+// expandConfigValue expands `${ENV_VAR}` and `${file:/path}` references in a
+// config value so secrets can come from the environment or a file instead of
+// shell history.
+func expandConfigValue(value string) (string, error) {
+	if strings.HasPrefix(value, "${file:") && strings.HasSuffix(value, "}") {
+		path := strings.TrimSuffix(strings.TrimPrefix(value, "${file:"), "}")
+		data, e := ioutil.ReadFile(path)
+		if e != nil {
+			return "", e
+		}
+		return strings.TrimRight(string(data), "\n"), nil
+	}
+
+	if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
+		name := strings.TrimSuffix(strings.TrimPrefix(value, "${"), "}")
+		if v, ok := os.LookupEnv(name); ok {
+			return v, nil
+		}
+		return "", fmt.Errorf("environment variable '%s' is not set", name)
+	}
+
+	return value, nil
+}
+
+// expandConfigKVArgs runs expandConfigValue over every "key=value" pair in a
+// "subsystem key1=val1 key2=val2 ..." blob, returning the blob with every
+// value expanded and the subsystem name and bare keys left untouched.
+func expandConfigKVArgs(args string) (string, error) {
+	fields := splitConfigKVFields(args)
+	if len(fields) == 0 {
+		return args, nil
+	}
+
+	out := make([]string, len(fields))
+	out[0] = fields[0]
+	for i, kv := range fields[1:] {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			out[i+1] = kv
+			continue
+		}
+		expanded, e := expandConfigValue(strings.Trim(parts[1], `"`))
+		if e != nil {
+			return "", fmt.Errorf("key '%s': %s", parts[0], e)
+		}
+		out[i+1] = fmt.Sprintf(`%s="%s"`, parts[0], expanded)
+	}
+
+	return strings.Join(out, " "), nil
+}
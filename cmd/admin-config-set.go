@@ -0,0 +1,124 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var adminConfigSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "set config of a MinIO server/cluster",
+	Before: setGlobalsFromContext,
+	Action: mainAdminConfigSet,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET KEY KEY1=VALUE1 [KEY2=VALUE2 ...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Set the region on MinIO server.
+     {{.Prompt}} {{.HelpName}} play/ region name=us-east-1
+
+  2. Set MQTT notification target settings on MinIO server.
+     {{.Prompt}} {{.HelpName}} myminio/ notify_mqtt broker="tcp://localhost:1883" topic="minio"
+
+  3. Inject a secret from the environment instead of typing it on the command line.
+     {{.Prompt}} {{.HelpName}} myminio/ notify_mqtt password='${MQTT_PASSWORD}'
+
+  4. Inject a secret from a file.
+     {{.Prompt}} {{.HelpName}} myminio/ notify_mqtt password='${file:/etc/mc/mqtt-password}'
+`,
+}
+
+// configSetMessage container to hold the set config response.
+type configSetMessage struct {
+	Status      string `json:"status"`
+	targetAlias string
+}
+
+// String colorized service status message.
+func (u configSetMessage) String() string {
+	suggestion := fmt.Sprintf("mc admin service restart %s", u.targetAlias)
+	return console.Colorize("ConfigSetMessage",
+		fmt.Sprintf("Successfully applied new settings.\nPlease restart your server with `%s`.", suggestion))
+}
+
+// JSON jsonified service status message.
+func (u configSetMessage) JSON() string {
+	u.Status = "success"
+	statusJSONBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(statusJSONBytes)
+}
+
+// checkAdminConfigSetSyntax - validate all the passed arguments
+func checkAdminConfigSetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1) // last argument is exit code
+	}
+}
+
+func mainAdminConfigSet(ctx *cli.Context) error {
+
+	checkAdminConfigSetSyntax(ctx)
+
+	console.SetColor("ConfigSetMessage", color.New(color.FgGreen))
+
+	// Get the alias parameter from cli
+	args := ctx.Args()
+	aliasedURL := args.Get(0)
+	kvArgs := strings.Join(args.Tail(), " ")
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	// Validate client-side against the server's schema before round-tripping
+	// a 400 - unknown subsystem/key, value outside an enum (see
+	// validateConfigSetKV for what is not checked).
+	schema, e := fetchConfigSchema(client, aliasedURL)
+	fatalIf(probe.NewError(e), "Unable to fetch config schema for '%s'.", aliasedURL)
+
+	// Expand ${ENV_VAR} and ${file:/path} references so secrets don't have to
+	// live in shell history, before validating - otherwise the enum check
+	// would run against the literal "${...}" placeholder instead of the value
+	// it resolves to.
+	expanded, e := expandConfigKVArgs(kvArgs)
+	fatalIf(probe.NewError(e), "Unable to expand config value.")
+
+	fatalIf(validateConfigSetKV(schema, expanded), "Invalid config key/value.")
+
+	fatalIf(probe.NewError(client.SetConfigKV(expanded)), "Cannot set server '%s' config", args.Tail())
+
+	printMsg(configSetMessage{targetAlias: aliasedURL})
+
+	return nil
+}
@@ -17,8 +17,10 @@
 package cmd
 
 import (
+	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cheggaaa/pb"
@@ -30,9 +32,30 @@ import (
 // progress extender.
 type progressBar struct {
 	*pb.ProgressBar
+	caption string
 }
 
-// newProgressBar - instantiate a progress bar.
+var (
+	sharedProgressGroupOnce sync.Once
+	sharedProgressGroupInst *progressGroup
+)
+
+// sharedProgressGroup returns the one progressGroup every newProgressBar call
+// registers its bar with, so that concurrent transfers (`cp`/`mirror`/`mv`
+// with `--parallel`, `rm --recursive`) render through a single coordinated
+// pb.Pool instead of each goroutine's bar printing over the others.
+func sharedProgressGroup() *progressGroup {
+	sharedProgressGroupOnce.Do(func() {
+		sharedProgressGroupInst = newProgressGroup(globalQuiet, globalJSON)
+	})
+	return sharedProgressGroupInst
+}
+
+// newProgressBar - instantiate a progress bar. Whenever the shared
+// progressGroup has a pool (stdout is a terminal and neither `-q` nor
+// `--json` is set), this bar is handed to that pool so it gets its own line
+// instead of fighting other bars for the cursor, even when it is the only
+// bar in flight; otherwise it falls back to the classic standalone ticker.
 func newProgressBar(total int64) *progressBar {
 	// Progress bar speific theme customization.
 	console.SetColor("Bar", color.New(color.FgGreen, color.Bold))
@@ -54,7 +77,8 @@ func newProgressBar(total int64) *progressBar {
 	// Show current speed is true.
 	bar.ShowSpeed = true
 
-	// Custom callback with colorized bar.
+	// Custom callback with colorized bar, used only in the standalone
+	// (non-pooled) fallback path below.
 	bar.Callback = func(s string) {
 		console.Print(console.Colorize("Bar", "\r"+s))
 	}
@@ -72,22 +96,40 @@ func newProgressBar(total int64) *progressBar {
 		bar.Format("[=> ]")
 	}
 
-	// Start the progress bar.
-	if bar.Total > 0 {
-		bar.Start()
-	}
-
 	// Copy for future
 	pgbar.ProgressBar = bar
 
+	if group := sharedProgressGroup(); group.pool != nil {
+		// The pool draws this bar itself; drop the standalone callback.
+		bar.Callback = nil
+		group.pool.Add(bar)
+		group.track(&pgbar)
+	} else if bar.Total > 0 {
+		// Start the progress bar.
+		bar.Start()
+	}
+
 	// Return new progress bar here.
 	return &pgbar
 }
 
-// Set caption.
+// isStdoutTerminal reports whether stdout is an interactive terminal, used
+// to decide whether a multi-bar dashboard can be drawn at all (a non-TTY
+// destination, e.g. a log file or CI pipe, can't host multiple live-updating
+// lines).
+func isStdoutTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return (fi.Mode() & os.ModeCharDevice) != 0
+}
+
+// Set caption. The raw caption is kept so a terminal resize can re-fixate it
+// to the new width instead of re-wrapping whatever was last rendered.
 func (p *progressBar) SetCaption(caption string) *progressBar {
-	caption = fixateBarCaption(caption, getFixedWidth(p.ProgressBar.GetWidth(), 18))
-	p.ProgressBar.Prefix(caption)
+	p.caption = caption
+	p.ProgressBar.Prefix(fixateBarCaption(caption, getFixedWidth(p.ProgressBar.GetWidth(), 18)))
 	return p
 }
 
@@ -162,3 +204,83 @@ func fixateBarCaption(caption string, width int) string {
 func getFixedWidth(width, percent int) int {
 	return width * percent / 100
 }
+
+// progressGroup is the shared pb.Pool every newProgressBar call registers its
+// bar with, so that concurrent transfers render as one multi-line dashboard
+// instead of each bar fighting the others for the cursor. It intentionally
+// does not track per-transfer byte counts or emit its own progress events:
+// nothing in this tree calls a transfer-tracking API on it, so the one that
+// shipped here earlier (Add/Update/Done, an aggregate bar, JSON heartbeats)
+// was dead code reachable only on paper. What's left is exactly what
+// newProgressBar actually drives: pool-based rendering plus resize handling.
+type progressGroup struct {
+	mutex  sync.Mutex
+	pool   *pb.Pool
+	bars   []*progressBar
+	closed bool
+}
+
+// newProgressGroup - instantiate the shared multi-bar pool. When stdout is
+// not a TTY, or quiet/JSON mode is requested, no pool is created and
+// newProgressBar falls back to its original standalone rendering. Terminal-
+// ness is always verified with isStdoutTerminal, never trusted purely on the
+// caller's say-so, since a caller forgetting to check before piping output
+// would otherwise spew raw bar escape codes into a log file.
+func newProgressGroup(quiet, jsonEnabled bool) *progressGroup {
+	console.SetColor("Bar", color.New(color.FgGreen, color.Bold))
+
+	if !isStdoutTerminal() {
+		quiet = true
+	}
+
+	pg := &progressGroup{}
+
+	if !quiet && !jsonEnabled {
+		pool, err := pb.NewPool()
+		if err == nil {
+			pg.pool = pool
+			pg.pool.Start()
+			watchTerminalResize(pg)
+		}
+	}
+
+	return pg
+}
+
+// track records a bar added to the pool so resizeCaptions can re-fixate it later.
+func (g *progressGroup) track(p *progressBar) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.bars = append(g.bars, p)
+}
+
+// resizeCaptions re-fixates every live bar's caption to the terminal's
+// current width, called whenever the terminal is resized so long object
+// names don't wrap or leave stale padding behind.
+func (g *progressGroup) resizeCaptions() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for _, p := range g.bars {
+		if p.caption == "" {
+			continue
+		}
+		p.ProgressBar.Prefix(fixateBarCaption(p.caption, getFixedWidth(p.ProgressBar.GetWidth(), 18)))
+	}
+}
+
+// Finish stops the pool, ending the dashboard.
+func (g *progressGroup) Finish() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.closed {
+		return
+	}
+	g.closed = true
+
+	if g.pool != nil {
+		g.pool.Stop()
+	}
+}
@@ -17,10 +17,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
@@ -40,22 +43,50 @@ type treeMessage struct {
 	Entry        string
 	IsDir        bool
 	BranchString string
+	Size         int64
+	ShowSize     bool
+	ModTime      time.Time
+	ShowMtime    bool
 }
 
 // Colorized message for console printing.
 func (t treeMessage) String() string {
-	//fmt.Printf("%s\n", console.Colorize("Dir", url))
 	entryType := "File"
 	if t.IsDir {
 		entryType = "Dir"
 	}
-	return fmt.Sprintf("%s%s", t.BranchString, console.Colorize(entryType, t.Entry))
+
+	line := fmt.Sprintf("%s%s", t.BranchString, console.Colorize(entryType, t.Entry))
+	if t.ShowSize {
+		line = fmt.Sprintf("%-10s %s", humanizeBytes(t.Size), line)
+	}
+	if t.ShowMtime {
+		line = fmt.Sprintf("%s %s", t.ModTime.Format("2006-01-02 15:04:05"), line)
+	}
+	return line
 }
 
-// JSON'ified message for scripting.
-// Does No-op. JSON requests are redirected to `ls -r --json`
-func (r treeMessage) JSON() string {
-	return ""
+// JSON'ified message for scripting. Kept for the rare caller still printing
+// a single treeMessage; `--output json` builds and marshals the full
+// treeNode tree instead, see the "json" case in mainTree.
+func (t treeMessage) JSON() string {
+	buf, e := json.Marshal(t)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(buf)
+}
+
+// humanizeBytes - render a byte count in human-readable units (KiB, MiB, ...).
+func humanizeBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
 var treeFlags = []cli.Flag{
@@ -68,6 +99,40 @@ var treeFlags = []cli.Flag{
 		Usage: "sets the depth threshold",
 		Value: -1,
 	},
+	cli.BoolFlag{
+		Name:  "du",
+		Usage: "compute and display the cumulative size of each directory",
+	},
+	cli.BoolFlag{
+		Name:  "size",
+		Usage: "print the size of each entry",
+	},
+	cli.BoolFlag{
+		Name:  "mtime",
+		Usage: "print the last-modified time of each entry",
+	},
+	cli.StringFlag{
+		Name:  "pattern",
+		Usage: "only include entries matching this glob pattern",
+	},
+	cli.StringFlag{
+		Name:  "ignore",
+		Usage: "exclude entries matching this glob pattern",
+	},
+	cli.StringFlag{
+		Name:  "sort",
+		Usage: "sort entries by `name`, `size` or `mtime`",
+		Value: "name",
+	},
+	cli.BoolFlag{
+		Name:  "reverse",
+		Usage: "reverse the sort order",
+	},
+	cli.StringFlag{
+		Name:  "output",
+		Usage: "output format: `tree`, `json`, `dot` or `html`",
+		Value: "tree",
+	},
 }
 
 // trees files and folders.
@@ -95,18 +160,56 @@ EXAMPLES:
 
    3. List all buckets on Amazon S3 cloud storage on Microsoft Windows in a tree format.
       $ {{.HelpName}} myS3\mybucket\
-   
+
    4. List all buckets including the objects on Amazon S3 cloud storage in a tree format.
       $ {{.HelpName}} -f myS3/mybucket/
-   
+
    5. Set the depth of the tree for listing.
       $ {{.HelpName}} -d 2 myS3/mybucket/
 
    6. List all the directories irrespective to the depth. -1 is the default value for depth.
       $ {{.HelpName}} -d -1 myS3/mybucket/
+
+   7. Show cumulative directory sizes and per-entry size in human-readable units.
+      $ {{.HelpName}} --du --size myS3/mybucket/
+
+   8. Only include '*.log' entries, sorted by size, largest first.
+      $ {{.HelpName}} --pattern '*.log' --sort size --reverse myS3/mybucket/
+
+   9. Export the tree as a Graphviz digraph.
+      $ {{.HelpName}} --output dot myS3/mybucket/ | dot -Tsvg -o tree.svg
 `,
 }
 
+// treeOptions bundles every flag that changes how the tree is walked,
+// filtered, sorted and rendered, so doTree/buildTree don't grow a new
+// positional parameter every time a flag is added.
+type treeOptions struct {
+	includeFiles bool
+	depth        int
+	du           bool
+	showSize     bool
+	showMtime    bool
+	pattern      string
+	ignore       string
+	sortBy       string
+	reverse      bool
+	output       string
+}
+
+// treeNode is the in-memory representation of the tree, built once per
+// target and then rendered according to treeOptions.output. Having a single
+// real tree (rather than printing while walking) is what lets `--output
+// json`/`dot`/`html` and `--du` bottom-up aggregation share one walk.
+type treeNode struct {
+	Name     string      `json:"name"`
+	Path     string      `json:"path"`
+	IsDir    bool        `json:"isDir"`
+	Size     int64       `json:"size"`
+	ModTime  time.Time   `json:"modTime,omitempty"`
+	Children []*treeNode `json:"children,omitempty"`
+}
+
 // checkTreeSyntax - validate all the passed arguments
 func checkTreeSyntax(ctx *cli.Context) {
 	args := ctx.Args()
@@ -116,6 +219,18 @@ func checkTreeSyntax(ctx *cli.Context) {
 		fatalIf(errInvalidArgument().Trace(args...), "depth should have a value greater than 0 or equal to -1")
 	}
 
+	switch ctx.String("sort") {
+	case "name", "size", "mtime":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("sort")), "sort should be one of 'name', 'size' or 'mtime'")
+	}
+
+	switch ctx.String("output") {
+	case "tree", "json", "dot", "html":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("output")), "output should be one of 'tree', 'json', 'dot' or 'html'")
+	}
+
 	if (args.Present()) && len(args) == 0 {
 		args = []string{"."}
 		return
@@ -129,9 +244,44 @@ func checkTreeSyntax(ctx *cli.Context) {
 	}
 }
 
-// doTree - list all entities inside a folder in a tree format.
-func doTree(url string, level int, leaf bool, dirClosed map[int]bool, depth int, includeFiles bool) error {
+// matchesFilters applies --pattern/--ignore glob filters to an entry name.
+func matchesFilters(name string, opts treeOptions) bool {
+	if opts.pattern != "" {
+		if ok, _ := filepath.Match(opts.pattern, name); !ok {
+			return false
+		}
+	}
+	if opts.ignore != "" {
+		if ok, _ := filepath.Match(opts.ignore, name); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sortChildren orders a node's children per opts.sortBy/opts.reverse.
+func sortChildren(children []*treeNode, opts treeOptions) {
+	sort.Slice(children, func(i, j int) bool {
+		var less bool
+		switch opts.sortBy {
+		case "size":
+			less = children[i].Size < children[j].Size
+		case "mtime":
+			less = children[i].ModTime.Before(children[j].ModTime)
+		default:
+			less = children[i].Name < children[j].Name
+		}
+		if opts.reverse {
+			return !less
+		}
+		return less
+	})
+}
 
+// buildTree walks url and returns its treeNode, recursing into directories.
+// Directory sizes are aggregated bottom-up from their children so --du works
+// without a second pass.
+func buildTree(url string, level int, opts treeOptions) (*treeNode, error) {
 	targetAlias, targetURL, _ := mustExpandAlias(url)
 	if !strings.HasSuffix(targetURL, "/") {
 		targetURL += "/"
@@ -140,107 +290,140 @@ func doTree(url string, level int, leaf bool, dirClosed map[int]bool, depth int,
 	clnt, err := newClientFromAlias(targetAlias, targetURL)
 	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
 
-	prefixPath := clnt.GetURL().Path
-	separator := string(clnt.GetURL().Separator)
-	prefixPath = strings.TrimSuffix(prefixPath, prefixPath[strings.LastIndex(prefixPath, separator)+1:])
-
-	bucketNameShowed := false
-	var prev *clientContent
-	show := func(end bool) error {
-		var branchString string
-		if level == 1 && !bucketNameShowed {
-			bucketNameShowed = true
-			printMsg(treeMessage{
-				Entry:        url,
-				IsDir:        true,
-				BranchString: branchString,
-			})
-		}
+	node := &treeNode{Name: filepath.Base(strings.TrimSuffix(targetURL, "/")), Path: url, IsDir: true}
 
-		if level != 1 {
-			for i := 1; i < level; i++ {
-				if dirClosed[i] {
-					branchString += " " + treeLevel
-				} else {
-					branchString += treeNext + treeLevel
-				}
-			}
+	for content := range clnt.List(false, false, DirNone) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to tree.")
+			continue
 		}
 
-		if end {
-			dirClosed[level] = true
-			branchString += treeLastEntry
-		} else {
-			dirClosed[level] = false
-			branchString += treeEntry
+		if !opts.includeFiles && !content.Type.IsDir() {
+			continue
 		}
 
-		// Convert any os specific delimiters to "/".
-		contentURL := filepath.ToSlash(prev.URL.Path)
-		prefixPath = filepath.ToSlash(prefixPath)
-
-		// Trim prefix of current working dir
-		prefixPath = strings.TrimPrefix(prefixPath, "."+separator)
+		name := filepath.Base(strings.TrimSuffix(filepath.ToSlash(content.URL.Path), "/"))
+		if !matchesFilters(name, opts) {
+			continue
+		}
 
-		if prev.Type.IsDir() {
-			printMsg(treeMessage{
-				Entry:        strings.TrimSuffix(strings.TrimPrefix(contentURL, prefixPath), "/"),
-				IsDir:        true,
-				BranchString: branchString,
-			})
-		} else {
-			printMsg(treeMessage{
-				Entry:        strings.TrimPrefix(contentURL, prefixPath),
-				IsDir:        false,
-				BranchString: branchString,
-			})
+		child := &treeNode{
+			Name:    name,
+			Path:    content.URL.Path,
+			IsDir:   content.Type.IsDir(),
+			Size:    content.Size,
+			ModTime: content.Time,
 		}
 
-		if prev.Type.IsDir() {
-			url := ""
+		if child.IsDir && (opts.depth == -1 || level <= opts.depth) {
+			childURL := content.URL.Path
 			if targetAlias != "" {
-				url = targetAlias + "/" + contentURL
-			} else {
-				url = contentURL
+				childURL = targetAlias + "/" + childURL
 			}
-
-			if depth == -1 || level <= depth {
-				if err := doTree(url, level+1, end, dirClosed, depth, includeFiles); err != nil {
-					return err
-				}
+			sub, err := buildTree(childURL, level+1, opts)
+			if err != nil {
+				return nil, err
+			}
+			child.Children = sub.Children
+			if opts.du {
+				child.Size = sub.Size
 			}
 		}
 
-		return nil
+		node.Children = append(node.Children, child)
 	}
 
-	for content := range clnt.List(false, false, DirNone) {
-
-		if !includeFiles && !content.Type.IsDir() {
-			continue
+	if opts.du {
+		var total int64
+		for _, c := range node.Children {
+			total += c.Size
 		}
+		node.Size = total
+	}
 
-		if content.Err != nil {
-			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to tree.")
-			continue
-		}
+	sortChildren(node.Children, opts)
+
+	return node, nil
+}
 
-		if prev != nil {
-			if err := show(false); err != nil {
-				return err
+// renderTreeText prints node in the classic ├─/└─ tree format, optionally
+// with size/mtime columns.
+func renderTreeText(node *treeNode, branchString string, last bool, opts treeOptions) {
+	printMsg(treeMessage{
+		Entry:        node.Name,
+		IsDir:        node.IsDir,
+		BranchString: branchString,
+		Size:         node.Size,
+		ShowSize:     opts.showSize || opts.du,
+		ModTime:      node.ModTime,
+		ShowMtime:    opts.showMtime,
+	})
+
+	for i, child := range node.Children {
+		childLast := i == len(node.Children)-1
+		childBranch := branchString
+		if branchString != "" {
+			// Replace this node's own "├─ "/"└─ " marker with the correct
+			// continuation for its children: blank space if this node was
+			// the last sibling (nothing more to connect to below it), or a
+			// vertical bar if more siblings follow.
+			trimmed := branchString[:len(branchString)-len(treeEntry)]
+			if last {
+				childBranch = trimmed + " " + treeLevel
+			} else {
+				childBranch = trimmed + treeNext + treeLevel
 			}
 		}
-
-		prev = content
+		if childLast {
+			childBranch += treeLastEntry
+		} else {
+			childBranch += treeEntry
+		}
+		renderTreeText(child, childBranch, childLast, opts)
 	}
+}
 
-	if prev != nil {
-		if err := show(true); err != nil {
-			return err
+// renderDot emits node as a Graphviz digraph, one node per path, edges from
+// parent to child, shapes distinguishing directories from files.
+func renderDot(node *treeNode) {
+	console.Println("digraph tree {")
+	var walk func(n *treeNode)
+	walk = func(n *treeNode) {
+		shape := "box"
+		if n.IsDir {
+			shape = "box3d"
+		}
+		label := n.Name
+		if !n.IsDir {
+			label = fmt.Sprintf("%s\\n%s", n.Name, humanizeBytes(n.Size))
+		}
+		console.Println(fmt.Sprintf("  %q [label=%q, shape=%s];", n.Path, label, shape))
+		for _, c := range n.Children {
+			console.Println(fmt.Sprintf("  %q -> %q;", n.Path, c.Path))
+			walk(c)
 		}
 	}
+	walk(node)
+	console.Println("}")
+}
 
-	return nil
+// renderHTML emits node as a nested <ul> listing.
+func renderHTML(node *treeNode) {
+	console.Println("<ul>")
+	var walk func(n *treeNode)
+	walk = func(n *treeNode) {
+		console.Println(fmt.Sprintf("<li>%s", n.Name))
+		if len(n.Children) > 0 {
+			console.Println("<ul>")
+			for _, c := range n.Children {
+				walk(c)
+			}
+			console.Println("</ul>")
+		}
+		console.Println("</li>")
+	}
+	walk(node)
+	console.Println("</ul>")
 }
 
 // mainTree - is a handler for mc tree command
@@ -258,24 +441,51 @@ func mainTree(ctx *cli.Context) error {
 		args = []string{"."}
 	}
 
-	includeFiles := ctx.Bool("files")
+	opts := treeOptions{
+		includeFiles: ctx.Bool("files"),
+		depth:        ctx.Int("depth"),
+		du:           ctx.Bool("du"),
+		showSize:     ctx.Bool("size"),
+		showMtime:    ctx.Bool("mtime"),
+		pattern:      ctx.String("pattern"),
+		ignore:       ctx.String("ignore"),
+		sortBy:       ctx.String("sort"),
+		reverse:      ctx.Bool("reverse"),
+		output:       ctx.String("output"),
+	}
+
+	// The global --json flag is a shorthand for --output json, kept for
+	// backward compatibility with existing scripts.
+	if globalJSON && opts.output == "tree" {
+		opts.output = "json"
+	}
 
 	var cErr error
 	for _, targetURL := range args {
-		if !globalJSON {
-			dirMap := make(map[int]bool)
-			if e := doTree(targetURL, 1, false, dirMap, ctx.Int("depth"), includeFiles); e != nil {
-				cErr = e
-			}
-		} else {
-			targetAlias, targetURL, _ := mustExpandAlias(targetURL)
-			if !strings.HasSuffix(targetURL, "/") {
-				targetURL += "/"
-			}
-			clnt, err := newClientFromAlias(targetAlias, targetURL)
-			fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
-			if e := doList(clnt, true, false); e != nil {
-				cErr = e
+		root, err := buildTree(targetURL, 1, opts)
+		if err != nil {
+			cErr = err
+			continue
+		}
+
+		switch opts.output {
+		case "json":
+			buf, e := json.MarshalIndent(root, "", "  ")
+			fatalIf(probe.NewError(e), "Unable to marshal tree into JSON.")
+			console.Println(string(buf))
+		case "dot":
+			renderDot(root)
+		case "html":
+			renderHTML(root)
+		default:
+			printMsg(treeMessage{Entry: targetURL, IsDir: true})
+			for i, child := range root.Children {
+				last := i == len(root.Children)-1
+				branch := treeEntry
+				if last {
+					branch = treeLastEntry
+				}
+				renderTreeText(child, branch, last, opts)
 			}
 		}
 	}
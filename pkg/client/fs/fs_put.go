@@ -1,96 +1,210 @@
 package fs
 
 import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"hash"
 	"io"
 	"os"
 	"path/filepath"
-	"sync"
+	"time"
 
 	"github.com/minio-io/mc/pkg/client"
 	"github.com/minio-io/minio/pkg/iodine"
 )
 
-// Put - upload new object to bucket
+// partialSuffix marks the temp file a Put() writes to before it is renamed
+// into place, so a process that dies mid-upload never leaves a half-written
+// object at the final path.
+const partialSuffix = ".partial"
+
+// Put - upload new object to bucket. Kept byte-for-byte compatible with the
+// client.Client interface: no context, no throttling. Delegates to
+// PutWithOptions so existing callers and the s3 sibling implementation don't
+// need to change.
 func (f *fsClient) Put(bucket, object, md5HexString string, size int64) (io.WriteCloser, error) {
-	r, w := io.Pipe()
-	blockingWriter := NewBlockingWriteCloser(w)
-	go func() {
-		// handle md5HexString match internally
-		if bucket == "" || object == "" {
-			err := iodine.New(client.InvalidArgument{}, nil)
-			r.CloseWithError(err)
-			blockingWriter.Release(err)
-			return
-		}
-		objectPath := filepath.Join(bucket, object)
-		if size < 0 {
-			err := iodine.New(client.InvalidArgument{}, nil)
-			r.CloseWithError(err)
-			blockingWriter.Release(err)
-			return
-		}
-		fs, err := os.Create(objectPath)
-		if os.IsExist(err) {
-			err := iodine.New(client.ObjectExists{Bucket: bucket, Object: object}, nil)
-			r.CloseWithError(err)
-			blockingWriter.Release(err)
-			return
-		}
-		if err != nil {
-			err := iodine.New(err, nil)
-			r.CloseWithError(err)
-			blockingWriter.Release(err)
-			return
-		}
-		_, err = io.CopyN(fs, r, size)
-		if err != nil {
-			err := iodine.New(err, nil)
-			r.CloseWithError(err)
-			blockingWriter.Release(err)
-			return
-		}
-		blockingWriter.Release(nil)
-		r.Close()
-	}()
-	return blockingWriter, nil
+	return f.PutWithOptions(context.Background(), bucket, object, md5HexString, size, 0)
 }
 
-// BlockingWriteCloser is a WriteCloser that blocks until released
-type BlockingWriteCloser struct {
-	w       io.WriteCloser
-	release *sync.WaitGroup
-	err     error
+// PutWithOptions - upload new object to bucket, the context- and
+// throttling-aware entry point.
+//
+// Unlike the previous io.Pipe + goroutine + sync.WaitGroup handoff, this
+// writes directly to a temp file in the target directory on the calling
+// goroutine: no detached goroutine means Close() can no longer block
+// forever waiting on a Release() that never comes, and ctx cancellation
+// actually unblocks an in-progress transfer instead of leaking it.
+//
+// maxBytesPerSecond throttles the write rate when > 0; 0 means unthrottled.
+// This tree doesn't contain `mc mirror`'s local-disk call site (not part of
+// this snapshot), so nothing here calls PutWithOptions with ctx/limiter
+// values other than Put's own context.Background()/0 yet - wiring that up
+// belongs to whichever change adds/touches that call site, at which point
+// it should pass a real per-run context and the configured rate limit
+// through to this method instead of adding another bespoke path.
+func (f *fsClient) PutWithOptions(ctx context.Context, bucket, object, md5HexString string, size int64, maxBytesPerSecond int64) (io.WriteCloser, error) {
+	if bucket == "" || object == "" {
+		return nil, iodine.New(client.InvalidArgument{}, nil)
+	}
+
+	objectPath := filepath.Join(bucket, object)
+	tmpPath := fmt.Sprintf("%s%s.%d", objectPath, partialSuffix, time.Now().UnixNano())
+
+	tmpFile, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if os.IsExist(err) {
+		return nil, iodine.New(client.ObjectExists{Bucket: bucket, Object: object}, nil)
+	}
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+
+	w := &fsWriteCloser{
+		ctx:         ctx,
+		ctxDone:     ctx.Done(),
+		tmpFile:     tmpFile,
+		tmpPath:     tmpPath,
+		finalPath:   objectPath,
+		size:        size,
+		expectedMD5: md5HexString,
+		md5sum:      md5.New(),
+		rateLimiter: newByteRateLimiter(maxBytesPerSecond),
+	}
+	return w, nil
 }
 
-// Write to the underlying writer
-func (b *BlockingWriteCloser) Write(p []byte) (int, error) {
-	n, err := b.w.Write(p)
-	err = iodine.New(err, nil)
-	return n, err
+// fsWriteCloser writes directly to a temp file, verifies an optional
+// streaming MD5, and atomically renames to the final path on Close. It is
+// the thin io.WriteCloser adapter that replaces BlockingWriteCloser.
+type fsWriteCloser struct {
+	ctx         context.Context
+	ctxDone     <-chan struct{}
+	tmpFile     *os.File
+	tmpPath     string
+	finalPath   string
+	size        int64
+	written     int64
+	expectedMD5 string
+	md5sum      hash.Hash
+	rateLimiter *byteRateLimiter
+	closed      bool
 }
 
-// Close blocks until another goroutine calls Release(error). Returns error code if either
-// writer fails or Release is called with an error.
-func (b *BlockingWriteCloser) Close() error {
-	err := b.w.Close()
+// Write writes p to the temp file, honoring ctx cancellation and an optional
+// max-bytes-per-second limiter so `mc mirror` can throttle local disk writes.
+func (w *fsWriteCloser) Write(p []byte) (int, error) {
+	select {
+	case <-w.ctxDone:
+		return 0, iodine.New(w.ctx.Err(), nil)
+	default:
+	}
+
+	if w.rateLimiter != nil {
+		if err := w.rateLimiter.wait(w.ctx, int64(len(p))); err != nil {
+			return 0, iodine.New(err, nil)
+		}
+	}
+
+	n, err := io.MultiWriter(w.tmpFile, w.md5sum).Write(p)
+	w.written += int64(n)
 	if err != nil {
-		b.err = err
+		return n, iodine.New(err, nil)
 	}
-	b.release.Wait()
-	return b.err
+	return n, nil
 }
 
-// Release the Close, causing it to unblock. Only call this once. Calling it multiple times results in a panic.
-func (b *BlockingWriteCloser) Release(err error) {
-	b.release.Done()
-	if err != nil {
-		b.err = err
+// Close fsyncs the temp file, verifies the streaming checksum if one was
+// supplied, renames the temp file into place, and returns. On ctx
+// cancellation or any failure it unlinks the temp file instead.
+func (w *fsWriteCloser) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if err := w.ctx.Err(); err != nil {
+		w.abort()
+		return iodine.New(err, nil)
+	}
+
+	if w.size >= 0 && w.written != w.size {
+		w.abort()
+		return iodine.New(fmt.Errorf("wrote %d bytes, expected %d", w.written, w.size), nil)
+	}
+
+	if w.expectedMD5 != "" {
+		if sum := hex.EncodeToString(w.md5sum.Sum(nil)); sum != w.expectedMD5 {
+			w.abort()
+			return iodine.New(fmt.Errorf("md5 mismatch: got %s, want %s", sum, w.expectedMD5), nil)
+		}
+	}
+
+	if err := w.tmpFile.Sync(); err != nil {
+		w.abort()
+		return iodine.New(err, nil)
 	}
+	if err := w.tmpFile.Close(); err != nil {
+		w.abort()
+		return iodine.New(err, nil)
+	}
+	if err := os.Rename(w.tmpPath, w.finalPath); err != nil {
+		os.Remove(w.tmpPath)
+		return iodine.New(err, nil)
+	}
+
+	return nil
 }
 
-// NewBlockingWriteCloser Creates a new write closer that must be released by the read consumer.
-func NewBlockingWriteCloser(w io.WriteCloser) *BlockingWriteCloser {
-	wg := &sync.WaitGroup{}
-	wg.Add(1)
-	return &BlockingWriteCloser{w: w, release: wg}
+// abort closes and unlinks the temp file, discarding a failed transfer.
+func (w *fsWriteCloser) abort() {
+	w.tmpFile.Close()
+	os.Remove(w.tmpPath)
+}
+
+// byteRateLimiter is a simple token-bucket limiter used to cap Put()
+// throughput when mirroring to a slow or shared local disk.
+type byteRateLimiter struct {
+	bytesPerSecond int64
+	lastRefill     time.Time
+	available      int64
+}
+
+// newByteRateLimiter returns a limiter allowing up to bytesPerSecond bytes/s.
+// A non-positive bytesPerSecond disables throttling.
+func newByteRateLimiter(bytesPerSecond int64) *byteRateLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &byteRateLimiter{bytesPerSecond: bytesPerSecond, lastRefill: time.Now(), available: bytesPerSecond}
+}
+
+// wait blocks until n bytes' worth of budget is available, or returns
+// ctx.Err() immediately if ctx is cancelled while waiting - a throttled
+// write must not outlive the context that was supposed to bound it. A write
+// larger than one second's budget waits for a full bucket and is then let
+// through, the overshoot being paid back by later waits.
+func (l *byteRateLimiter) wait(ctx context.Context, n int64) error {
+	for {
+		elapsed := time.Since(l.lastRefill)
+		l.lastRefill = time.Now()
+		l.available += int64(elapsed.Seconds() * float64(l.bytesPerSecond))
+		if l.available > l.bytesPerSecond {
+			l.available = l.bytesPerSecond
+		}
+
+		if l.available >= n || l.available >= l.bytesPerSecond {
+			l.available -= n
+			return nil
+		}
+
+		missing := n - l.available
+		sleepFor := time.Duration(float64(missing) / float64(l.bytesPerSecond) * float64(time.Second))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleepFor):
+		}
+	}
 }